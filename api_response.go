@@ -0,0 +1,57 @@
+package stripe
+
+import "net/http"
+
+// APIResponse carries the raw HTTP details of a single call to the Stripe
+// API, so that callers can recover the Request-Id for support tickets or log
+// the response status for observability, without changing the (*T, error)
+// signatures of the client methods.
+type APIResponse struct {
+	Header     http.Header
+	StatusCode int
+	RequestID  string
+	RawBody    []byte
+
+	// IdempotencyKey is the key that was sent on the originating request, if
+	// any. Populated whether the key was user-supplied or generated.
+	IdempotencyKey string
+}
+
+// APIResource should be embedded in any struct returned by query(), so that
+// it satisfies lastResponseSetter and query() can populate LastResponse
+// after a successful unmarshal.
+type APIResource struct {
+	LastResponse *APIResponse `json:"-" bson:"-"`
+}
+
+// SetLastResponse implements lastResponseSetter.
+func (a *APIResource) SetLastResponse(resp *APIResponse) {
+	a.LastResponse = resp
+}
+
+// GetLastResponse returns the most recently set LastResponse, so that
+// generic code can read it back without knowing the concrete resource type.
+func (a *APIResource) GetLastResponse() *APIResponse {
+	return a.LastResponse
+}
+
+// lastResponseSetter is implemented by any type embedding APIResource.
+// query() type-asserts the destination (and, for list responses, each
+// element of Data) against this interface to populate LastResponse via
+// reflection rather than requiring every resource to duplicate the field.
+type lastResponseSetter interface {
+	SetLastResponse(*APIResponse)
+}
+
+// newAPIResponse builds an APIResponse from a completed http.Response. body
+// is the already-drained response body, since resp.Body has already been
+// read in full by doRequest.
+func newAPIResponse(resp *http.Response, body []byte, idempotencyKey string) *APIResponse {
+	return &APIResponse{
+		Header:         resp.Header,
+		StatusCode:     resp.StatusCode,
+		RequestID:      resp.Header.Get("Request-Id"),
+		RawBody:        body,
+		IdempotencyKey: idempotencyKey,
+	}
+}
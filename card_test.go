@@ -0,0 +1,67 @@
+package stripe
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAppendCardParamsToValuesRequiredFields(t *testing.T) {
+	c := &CardParams{Number: "4242424242424242", ExpMonth: 12, ExpYear: 2030}
+	values := url.Values{}
+	appendCardParamsToValues(c, &values)
+
+	if got := values.Get("card[number]"); got != c.Number {
+		t.Errorf("expected card[number] %q, got %q", c.Number, got)
+	}
+	if got := values.Get("card[exp_month]"); got != "12" {
+		t.Errorf("expected card[exp_month] 12, got %q", got)
+	}
+	if got := values.Get("card[exp_year]"); got != "2030" {
+		t.Errorf("expected card[exp_year] 2030, got %q", got)
+	}
+	if values.Has("card[cvc]") || values.Has("card[name]") {
+		t.Error("expected unset optional fields to be omitted")
+	}
+}
+
+func TestAppendCardParamsToValuesOptionalFields(t *testing.T) {
+	c := &CardParams{
+		Number:     "4242424242424242",
+		ExpMonth:   1,
+		ExpYear:    2030,
+		CVC:        "123",
+		Name:       "Jane Doe",
+		Address1:   "123 Main St",
+		AddressZip: "94103",
+	}
+	values := url.Values{}
+	appendCardParamsToValues(c, &values)
+
+	if got := values.Get("card[cvc]"); got != "123" {
+		t.Errorf("expected card[cvc] 123, got %q", got)
+	}
+	if got := values.Get("card[name]"); got != "Jane Doe" {
+		t.Errorf("expected card[name] %q, got %q", "Jane Doe", got)
+	}
+	if got := values.Get("card[address_line1]"); got != "123 Main St" {
+		t.Errorf("expected card[address_line1] %q, got %q", "123 Main St", got)
+	}
+	if got := values.Get("card[address_zip]"); got != "94103" {
+		t.Errorf("expected card[address_zip] %q, got %q", "94103", got)
+	}
+}
+
+func TestPaymentIntentActionRequiredErrorMessage(t *testing.T) {
+	err := &PaymentIntentActionRequiredError{PaymentIntent: &PaymentIntent{Id: "pi_123", Status: "requires_action"}}
+	msg := err.Error()
+	if msg == "" {
+		t.Fatal("expected a non-empty error message")
+	}
+	if want := "pi_123"; !strings.Contains(msg, want) {
+		t.Errorf("expected error message to mention %q, got %q", want, msg)
+	}
+	if want := "requires_action"; !strings.Contains(msg, want) {
+		t.Errorf("expected error message to mention %q, got %q", want, msg)
+	}
+}
@@ -1,6 +1,8 @@
 package stripe
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 )
@@ -21,6 +23,7 @@ const (
 //
 // see https://stripe.com/docs/api#charge_object
 type Charge struct {
+	APIResource    `json:"-" bson:"-"`
 	Id             string        `json:"id" bson:"id"`
 	Desc           String        `json:"description" bson:"description"`
 	Amount         int64         `json:"amount" bson:"amount"`
@@ -67,6 +70,19 @@ type ChargeParams struct {
 	// (Optional) Credit Card token that should be charged.
 	Token string
 
+	// (Optional) The ID of a PaymentMethod to use instead of Card or Token.
+	// When set, the charge is created through the PaymentIntents flow so that
+	// SCA (3DS2) confirmation can be required by Stripe if needed.
+	PaymentMethod string
+
+	// (Optional) Set to true to attempt to confirm the underlying
+	// PaymentIntent immediately. Only used when PaymentMethod is set.
+	Confirm bool
+
+	// (Optional) Set to true to indicate that the customer is not present in
+	// the checkout flow. Only used when PaymentMethod is set.
+	OffSession bool
+
 	// An arbitrary string which you can attach to a charge object. It is
 	// displayed when in the web interface alongside the charge. It's often a
 	// good idea to use an email address as a description for tracking later.
@@ -81,6 +97,20 @@ type ChargeClient struct{}
 //
 // see https://stripe.com/docs/api#create_charge
 func (self *ChargeClient) Create(params *ChargeParams) (*Charge, error) {
+	return self.CreateCtx(context.Background(), params)
+}
+
+// CreateCtx creates a new credit card Charge, honoring ctx for cancellation,
+// deadlines, and any RequestOptions attached via WithRequestOptions.
+//
+// see https://stripe.com/docs/api#create_charge
+func (self *ChargeClient) CreateCtx(ctx context.Context, params *ChargeParams) (*Charge, error) {
+	// charges made with a PaymentMethod go through the PaymentIntents flow so
+	// that Stripe can require SCA (3DS2) confirmation when necessary
+	if params.PaymentMethod != "" {
+		return self.createWithPaymentIntent(ctx, params)
+	}
+
 	charge := Charge{}
 	values := url.Values{
 		"amount":      {strconv.FormatInt(params.Amount, 10)},
@@ -98,17 +128,66 @@ func (self *ChargeClient) Create(params *ChargeParams) (*Charge, error) {
 		values.Add("customer", params.Customer)
 	}
 
-	err := query("POST", "/v1/charges", values, &charge)
+	err := queryCtx(ctx, "POST", "/v1/charges", values, &charge)
 	return &charge, err
 }
 
+// createWithPaymentIntent charges params.PaymentMethod through the
+// PaymentIntents API and returns the resulting Charge, so that callers who
+// only know the legacy ChargeClient.Create path can opt into SCA support
+// without switching to PaymentIntents directly.
+func (self *ChargeClient) createWithPaymentIntent(ctx context.Context, params *ChargeParams) (*Charge, error) {
+	pi, err := PaymentIntents.CreateCtx(ctx, &PaymentIntentParams{
+		Amount:        params.Amount,
+		Currency:      params.Currency,
+		Customer:      params.Customer,
+		PaymentMethod: params.PaymentMethod,
+		Confirm:       params.Confirm,
+		OffSession:    params.OffSession,
+		Desc:          params.Desc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pi.Charges != nil && len(pi.Charges.Data) > 0 {
+		return pi.Charges.Data[0], nil
+	}
+	// the PaymentIntent didn't produce a Charge yet, most commonly because
+	// it's sitting in requires_action (the SCA/3DS2 confirmation flow this
+	// path exists for) or requires_confirmation; surface the PaymentIntent
+	// itself so the caller can inspect Status/ClientSecret/NextAction and
+	// drive the customer through it, rather than looking like a silent
+	// no-op success.
+	return nil, &PaymentIntentActionRequiredError{PaymentIntent: pi}
+}
+
+// PaymentIntentActionRequiredError is returned by ChargeClient.Create (via
+// the PaymentMethod/Confirm path) when the underlying PaymentIntent has no
+// Charge yet, for example because it requires further action from the
+// customer, such as 3DS2 authentication, or has not been confirmed.
+type PaymentIntentActionRequiredError struct {
+	PaymentIntent *PaymentIntent
+}
+
+func (e *PaymentIntentActionRequiredError) Error() string {
+	return fmt.Sprintf("stripe: payment intent %s requires further action (status %s)", e.PaymentIntent.Id, e.PaymentIntent.Status)
+}
+
 // Retrieves the details of a charge with the given ID.
 //
 // see https://stripe.com/docs/api#retrieve_charge
 func (self *ChargeClient) Retrieve(id string) (*Charge, error) {
+	return self.RetrieveCtx(context.Background(), id)
+}
+
+// RetrieveCtx retrieves the details of a charge with the given ID, honoring
+// ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_charge
+func (self *ChargeClient) RetrieveCtx(ctx context.Context, id string) (*Charge, error) {
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &charge)
+	err := queryCtx(ctx, "GET", path, nil, &charge)
 	return &charge, err
 }
 
@@ -116,10 +195,18 @@ func (self *ChargeClient) Retrieve(id string) (*Charge, error) {
 //
 // see https://stripe.com/docs/api#refund_charge
 func (self *ChargeClient) Refund(id string) (*Charge, error) {
+	return self.RefundCtx(context.Background(), id)
+}
+
+// RefundCtx refunds a charge for the full amount, honoring ctx for
+// cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#refund_charge
+func (self *ChargeClient) RefundCtx(ctx context.Context, id string) (*Charge, error) {
 	values := url.Values{}
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id) + "/refund"
-	err := query("POST", path, values, &charge)
+	err := queryCtx(ctx, "POST", path, values, &charge)
 	return &charge, err
 }
 
@@ -127,17 +214,130 @@ func (self *ChargeClient) Refund(id string) (*Charge, error) {
 //
 // see https://stripe.com/docs/api#refund_charge
 func (self *ChargeClient) RefundAmount(id string, amt int64) (*Charge, error) {
+	return self.RefundAmountCtx(context.Background(), id, amt)
+}
+
+// RefundAmountCtx refunds a charge for the specified amount, honoring ctx
+// for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#refund_charge
+func (self *ChargeClient) RefundAmountCtx(ctx context.Context, id string, amt int64) (*Charge, error) {
 	values := url.Values{
 		"amount": {strconv.FormatInt(amt, 10)},
 	}
 	charge := Charge{}
 	path := "/v1/charges/" + url.QueryEscape(id) + "/refund"
-	err := query("POST", path, values, &charge)
+	err := queryCtx(ctx, "POST", path, values, &charge)
 	return &charge, err
 }
 
+// ChargeListParams encapsulates options for listing Charges with
+// cursor-based pagination.
+type ChargeListParams struct {
+	ListParams
+
+	// (Optional) Only return charges for this Customer ID.
+	Customer string
+}
+
+// ChargeIter is a lazily-paginated list of Charges, as returned by
+// ChargeClient.Iter. It fetches one page at a time as the caller ranges
+// over it, so it is safe to use over result sets far larger than a single
+// page.
+type ChargeIter struct {
+	ctx     context.Context
+	params  *ChargeListParams
+	page    []*Charge
+	idx     int
+	hasMore bool
+	err     error
+}
+
+// Next advances the iterator to the next Charge, fetching another page from
+// Stripe if the current one is exhausted. It returns false once there are
+// no more Charges or an error occurs; check Err to distinguish the two.
+func (it *ChargeIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	if it.idx < len(it.page) {
+		return true
+	}
+	if it.page != nil && !it.hasMore {
+		return false
+	}
+	if it.page != nil {
+		it.params.StartingAfter = it.page[len(it.page)-1].Id
+	}
+
+	resp, err := chargeListPage(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Data
+	it.hasMore = resp.HasMore
+	it.idx = 0
+	return len(it.page) > 0
+}
+
+// Charge returns the Charge at the iterator's current position. Only valid
+// after a call to Next that returned true.
+func (it *ChargeIter) Charge() *Charge {
+	return it.page[it.idx]
+}
+
+// Err returns the first error encountered while paginating, if any.
+func (it *ChargeIter) Err() error {
+	return it.err
+}
+
+// Iter returns a ChargeIter over the Charges matching the given params,
+// using cursor-based pagination (limit/starting_after/ending_before). Unlike
+// List/ListN, which take count/offset and collect the results into a slice,
+// it fetches each page only as the caller ranges over it.
+//
+// see https://stripe.com/docs/api#list_charges
+func (self *ChargeClient) Iter(params *ChargeListParams) *ChargeIter {
+	return self.IterCtx(context.Background(), params)
+}
+
+// IterCtx is like Iter, but every page fetched while ranging over the
+// returned ChargeIter honors ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#list_charges
+func (self *ChargeClient) IterCtx(ctx context.Context, params *ChargeListParams) *ChargeIter {
+	if params == nil {
+		params = &ChargeListParams{}
+	}
+	return &ChargeIter{ctx: ctx, params: params}
+}
+
+type chargeListResp struct {
+	APIResource `json:"-" bson:"-"`
+	Data        []*Charge `json:"data"`
+	HasMore     bool      `json:"has_more"`
+}
+
+func chargeListPage(ctx context.Context, params *ChargeListParams) (*chargeListResp, error) {
+	values := url.Values{}
+	params.ListParams.appendTo(&values)
+	if params.Customer != "" {
+		values.Set("customer", params.Customer)
+	}
+
+	resp := chargeListResp{}
+	err := queryCtx(ctx, "GET", "/v1/charges", values, &resp)
+	return &resp, err
+}
+
 // Returns a list of your Charges.
 //
+// Deprecated: use Iter, which takes cursor-based ListParams instead of
+// count/offset and fetches pages lazily.
+//
 // see https://stripe.com/docs/api#list_charges
 func (self *ChargeClient) List() ([]*Charge, error) {
 	return self.list("", 10, 0)
@@ -164,26 +364,33 @@ func (self *ChargeClient) CustomerListN(id string, count int, offset int) ([]*Ch
 	return self.list(id, count, offset)
 }
 
+// list is the count/offset-compatible implementation behind the deprecated
+// List/ListN methods. It walks the same cursor-based pages Iter uses (count
+// becomes the page Limit, capped at 100) and skips the first offset results
+// on the client side, since Stripe's list endpoints no longer support
+// numeric offsets directly.
 func (self *ChargeClient) list(id string, count int, offset int) ([]*Charge, error) {
-	// define a wrapper function for the Charge List, so that we can
-	// cleanly parse the JSON
-	type listChargesResp struct{ Data []*Charge }
-	resp := listChargesResp{}
-
-	// add the count and offset to the list of url values
-	values := url.Values{
-		"count":  {strconv.Itoa(count)},
-		"offset": {strconv.Itoa(offset)},
+	limit := count
+	if limit <= 0 || limit > 100 {
+		limit = 100
 	}
+	params := &ChargeListParams{ListParams: ListParams{Limit: limit}, Customer: id}
+	iter := self.IterCtx(context.Background(), params)
 
-	// query for customer id, if provided
-	if id != "" {
-		values.Add("customer", id)
+	out := make([]*Charge, 0, count)
+	skipped := 0
+	for iter.Next() {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		out = append(out, iter.Charge())
+		if len(out) >= count {
+			break
+		}
 	}
-
-	err := query("GET", "/v1/charges", values, &resp)
-	if err != nil {
+	if err := iter.Err(); err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	return out, nil
 }
@@ -0,0 +1,204 @@
+package stripe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Key is the Stripe secret API key used to authenticate every request made
+// through query(). Set it once at startup, e.g. stripe.Key = "sk_test_...".
+var Key string
+
+// apiBase is the root of the Stripe API. It's a var so tests can point it at
+// a local server.
+var apiBase = "https://api.stripe.com"
+
+// httpClient is the http.Client used to issue every request. It's a var so
+// tests can swap in a client with a custom Transport.
+var httpClient = &http.Client{}
+
+// SetKeyEnv sets Key from the STRIPE_API_KEY environment variable, for the
+// common case of keeping the key out of source control.
+func SetKeyEnv() error {
+	key := os.Getenv("STRIPE_API_KEY")
+	if key == "" {
+		return errors.New("stripe: STRIPE_API_KEY not found in environment")
+	}
+	Key = key
+	return nil
+}
+
+// StatusError is returned by query() when Stripe responds with a non-2xx
+// status. Callers that need the raw body or status (beyond what query()
+// already uses to decide whether to retry) can type-assert for it.
+type StatusError struct {
+	StatusCode int
+	RequestID  string
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("stripe: %d response (request %s): %s", e.StatusCode, e.RequestID, e.Body)
+}
+
+// query issues a single logical Stripe API request and decodes the JSON
+// response into v. method/path/values describe the request as they always
+// have; opts carries any RequestOption values (e.g. WithIdempotencyKey) for this
+// call. ctx is threaded down to the underlying http.Request via
+// http.NewRequestWithContext, and also governs how long the retry loop's
+// backoff sleeps will wait before giving up.
+//
+// On a network error or a status code in currentConfig.RetriableStatusCodes,
+// the request is retried up to currentConfig.MaxRetries times with
+// exponential backoff (see backoffDuration). Every POST/DELETE carries an
+// Idempotency-Key header, generated in buildRequestOptions if the caller
+// didn't supply one, so that retries are safe even if an earlier attempt
+// actually reached Stripe before failing.
+func query(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	hooks := currentConfig.Hooks
+	ctx = hooks.beginRequest(ctx, method, path)
+
+	ro := buildRequestOptions(opts)
+
+	var resp *APIResponse
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			hooks.retry(ctx, attempt, err)
+
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				hooks.endRequest(ctx, resp, err)
+				return err
+			case <-time.After(backoffDuration(attempt-1, currentConfig.MaxBackoff)):
+			}
+		}
+
+		resp, err = doRequest(ctx, method, path, values, v, ro)
+		if err == nil || attempt >= currentConfig.MaxRetries || !isRetriableError(resp, err) {
+			break
+		}
+	}
+
+	hooks.endRequest(ctx, resp, err)
+	return err
+}
+
+// isRetriableError reports whether err, as returned by doRequest, is safe to
+// retry: either a transport-level failure (no response was received) or a
+// StatusError whose code is in currentConfig.RetriableStatusCodes. A
+// successful response whose body failed to decode is not retried.
+func isRetriableError(resp *APIResponse, err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return isRetriableStatus(se.StatusCode)
+	}
+	return resp == nil
+}
+
+// doRequest performs a single HTTP attempt and, on success, decodes the
+// response into v and populates LastResponse on v and (for list responses)
+// each element of v's Data field.
+func doRequest(ctx context.Context, method, path string, values url.Values, v interface{}, ro *requestOptions) (*APIResponse, error) {
+	reqURL := apiBase + path
+
+	var body io.Reader
+	if method == "GET" {
+		if values != nil && len(values) > 0 {
+			reqURL += "?" + values.Encode()
+		}
+	} else {
+		body = bytes.NewBufferString(values.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if method != "GET" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	apiKey := Key
+	account := ""
+	if requestOpts, ok := requestOptionsFromContext(ctx); ok {
+		if requestOpts.APIKey != "" {
+			apiKey = requestOpts.APIKey
+		}
+		account = requestOpts.Account
+	}
+	req.SetBasicAuth(apiKey, "")
+	if account != "" {
+		req.Header.Set("Stripe-Account", account)
+	}
+	sentKey := ""
+	if method == "POST" || method == "DELETE" {
+		req.Header.Set("Idempotency-Key", ro.idempotencyKey)
+		sentKey = ro.idempotencyKey
+	}
+
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResp := newAPIResponse(httpResp, raw, sentKey)
+
+	if httpResp.StatusCode >= 300 {
+		return apiResp, &StatusError{StatusCode: httpResp.StatusCode, RequestID: apiResp.RequestID, Body: raw}
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(raw, v); err != nil {
+			return apiResp, err
+		}
+		setLastResponse(v, apiResp)
+	}
+
+	return apiResp, nil
+}
+
+// setLastResponse populates LastResponse on v, and on each element of v's
+// Data field if v is a list response wrapper, via the lastResponseSetter
+// interface. Both the outer wrapper (e.g. chargeListResp) and each element
+// (e.g. *Charge) are expected to embed APIResource.
+func setLastResponse(v interface{}, resp *APIResponse) {
+	if lrs, ok := v.(lastResponseSetter); ok {
+		lrs.SetLastResponse(resp)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+
+	data := rv.FieldByName("Data")
+	if !data.IsValid() || data.Kind() != reflect.Slice {
+		return
+	}
+	for i := 0; i < data.Len(); i++ {
+		if elem, ok := data.Index(i).Interface().(lastResponseSetter); ok {
+			elem.SetLastResponse(resp)
+		}
+	}
+}
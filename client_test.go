@@ -0,0 +1,153 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// withTestServer points apiBase at a local httptest.Server for the rest of
+// the test, and restores it on cleanup.
+func withTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	prevBase := apiBase
+	apiBase = srv.URL
+	t.Cleanup(func() { apiBase = prevBase })
+
+	return srv
+}
+
+func withFastRetries(t *testing.T) {
+	t.Helper()
+	prev := currentConfig
+	c := defaultConfig
+	c.MaxBackoff = time.Millisecond
+	currentConfig = c
+	t.Cleanup(func() { currentConfig = prev })
+}
+
+func TestQueryRetriesOnServerErrorThenSucceeds(t *testing.T) {
+	withFastRetries(t)
+
+	var attempts int
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge)
+	if err != nil {
+		t.Fatalf("expected eventual success, got error %s", err.Error())
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts (2 failures + 1 success), got %d", attempts)
+	}
+	if charge.Id != "ch_123" {
+		t.Errorf("expected Id %q, got %q", "ch_123", charge.Id)
+	}
+}
+
+func TestQueryGivesUpAfterMaxRetries(t *testing.T) {
+	withFastRetries(t)
+
+	var attempts int
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	charge := Charge{}
+	err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge)
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if want := currentConfig.MaxRetries + 1; attempts != want {
+		t.Errorf("expected %d attempts (1 initial + %d retries), got %d", want, currentConfig.MaxRetries, attempts)
+	}
+}
+
+func TestQueryDoesNotRetryNonRetriableStatus(t *testing.T) {
+	withFastRetries(t)
+
+	var attempts int
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	charge := Charge{}
+	err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge)
+	if err == nil {
+		t.Fatal("expected an error for a 400 response")
+	}
+	if attempts != 1 {
+		t.Errorf("expected a 400 response not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestQuerySetsIdempotencyKeyHeaderOnPost(t *testing.T) {
+	withFastRetries(t)
+
+	var gotKey string
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("Idempotency-Key")
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	if err := queryCtx(context.Background(), "POST", "/v1/charges", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if gotKey == "" {
+		t.Error("expected an Idempotency-Key header to be set on a POST request")
+	}
+}
+
+func TestQueryHonorsExplicitIdempotencyKey(t *testing.T) {
+	withFastRetries(t)
+
+	var gotKey string
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("Idempotency-Key")
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	err := queryCtx(context.Background(), "POST", "/v1/charges", nil, &charge, WithIdempotencyKey("my-key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if gotKey != "my-key" {
+		t.Errorf("expected Idempotency-Key %q, got %q", "my-key", gotKey)
+	}
+}
+
+func TestQueryPopulatesLastResponse(t *testing.T) {
+	withFastRetries(t)
+
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req_123")
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	if err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if charge.LastResponse == nil {
+		t.Fatal("expected LastResponse to be populated")
+	}
+	if charge.LastResponse.RequestID != "req_123" {
+		t.Errorf("expected RequestID %q, got %q", "req_123", charge.LastResponse.RequestID)
+	}
+}
@@ -0,0 +1,56 @@
+package stripe
+
+import "time"
+
+// Config controls package-wide behavior of the Stripe client, such as the
+// retry policy query() applies to every request. It is safe to
+// override at startup, before any requests are made.
+type Config struct {
+	// MaxRetries is the maximum number of times a request will be retried
+	// after a network error or a response whose status is in
+	// RetriableStatusCodes.
+	MaxRetries int
+
+	// MaxBackoff is the ceiling for the exponential backoff delay between
+	// retries, before up to 25% jitter is added on top.
+	MaxBackoff time.Duration
+
+	// RetriableStatusCodes lists the HTTP status codes that are safe to
+	// retry, in addition to network errors.
+	RetriableStatusCodes map[int]bool
+
+	// Hooks lets callers observe requests for logging, metrics, or tracing.
+	// All fields are optional; see Hooks for details.
+	Hooks Hooks
+}
+
+// defaultConfig mirrors the retry behavior of Stripe's official client
+// libraries: three retries, capped at five seconds, on lock contention and
+// server errors.
+var defaultConfig = Config{
+	MaxRetries: 3,
+	MaxBackoff: 5 * time.Second,
+	RetriableStatusCodes: map[int]bool{
+		409: true,
+		500: true,
+		502: true,
+		503: true,
+		504: true,
+	},
+}
+
+// currentConfig is the package-level Config consulted by query() on every
+// request.
+var currentConfig = defaultConfig
+
+// SetConfig overrides the package-wide retry configuration. Pass a Config
+// built from defaultConfig's values to tweak just one field.
+func SetConfig(c Config) {
+	currentConfig = c
+}
+
+// isRetriableStatus reports whether a response with the given status code
+// should be retried under the current Config.
+func isRetriableStatus(statusCode int) bool {
+	return currentConfig.RetriableStatusCodes[statusCode]
+}
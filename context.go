@@ -0,0 +1,75 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// RequestOptions carries per-request overrides that apply to a single
+// logical call rather than the whole package, such as acting on behalf of a
+// connected account, pinning an idempotency key across an operation made up
+// of several requests, or giving one call a tighter deadline than its
+// surrounding context already has. Attach them to a context with
+// WithRequestOptions; the Ctx variants of each client method (e.g.
+// Charges.CreateCtx) pass them through to query() without needing an extra
+// parameter on every call.
+type RequestOptions struct {
+	// APIKey overrides the package-level Key for this request only.
+	APIKey string
+
+	// Account, if set, is sent as the Stripe-Account header so the request
+	// acts on behalf of the given connected account (Stripe Connect).
+	Account string
+
+	// IdempotencyKey overrides the key query() would otherwise generate or
+	// accept via WithIdempotencyKey, so that a retried higher-level
+	// operation (e.g. a caller's own retry around CreateCtx) reuses the same
+	// key across attempts.
+	IdempotencyKey string
+
+	// Timeout bounds this request only, independent of any deadline already
+	// on the context passed to the Ctx method.
+	Timeout time.Duration
+}
+
+// requestOptionsKey is the context key RequestOptions are stored under.
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying opts, for consumption by
+// query() on any Ctx client method called with the resulting context.
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// requestOptionsFromContext returns the RequestOptions attached to ctx via
+// WithRequestOptions, if any.
+func requestOptionsFromContext(ctx context.Context) (RequestOptions, bool) {
+	opts, ok := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return opts, ok
+}
+
+// queryCtx is the context-aware counterpart to query(): it honors a
+// deadline or cancellation already carried by ctx, applies any
+// RequestOptions attached via WithRequestOptions (idempotency key override,
+// connected account, per-request timeout), and threads ctx down to the
+// underlying HTTP transport via http.NewRequestWithContext so that retry
+// backoff sleeps can be interrupted early.
+func queryCtx(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ro, ok := requestOptionsFromContext(ctx); ok {
+		if ro.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, ro.Timeout)
+			defer cancel()
+		}
+		if ro.IdempotencyKey != "" {
+			opts = append(opts, WithIdempotencyKey(ro.IdempotencyKey))
+		}
+	}
+
+	return query(ctx, method, path, values, v, opts...)
+}
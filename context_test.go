@@ -0,0 +1,71 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueryCtxAppliesRequestOptionsIdempotencyKey(t *testing.T) {
+	withFastRetries(t)
+
+	var gotKey string
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("Idempotency-Key")
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{IdempotencyKey: "op-wide-key"})
+	charge := Charge{}
+	if err := queryCtx(ctx, "POST", "/v1/charges", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if gotKey != "op-wide-key" {
+		t.Errorf("expected Idempotency-Key %q from RequestOptions, got %q", "op-wide-key", gotKey)
+	}
+}
+
+func TestQueryCtxAppliesRequestOptionsAccount(t *testing.T) {
+	withFastRetries(t)
+
+	var gotAccount string
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotAccount = r.Header.Get("Stripe-Account")
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{Account: "acct_123"})
+	charge := Charge{}
+	if err := queryCtx(ctx, "GET", "/v1/charges/ch_123", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if gotAccount != "acct_123" {
+		t.Errorf("expected Stripe-Account %q, got %q", "acct_123", gotAccount)
+	}
+}
+
+func TestQueryCtxRequestOptionsTimeoutExpires(t *testing.T) {
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{Timeout: time.Millisecond})
+	charge := Charge{}
+	err := queryCtx(ctx, "GET", "/v1/charges/ch_123", nil, &charge)
+	if err == nil {
+		t.Fatal("expected the per-request Timeout to cause an error")
+	}
+}
+
+func TestQueryCtxHonorsAlreadyCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	charge := Charge{}
+	err := queryCtx(ctx, "GET", "/v1/charges/ch_123", nil, &charge)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
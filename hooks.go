@@ -0,0 +1,48 @@
+package stripe
+
+import "context"
+
+// Hooks lets callers observe requests made by query() without the package
+// taking a hard dependency on any particular metrics or tracing library.
+// Every field is optional; a nil hook is simply skipped. See the stripeprom
+// and stripetrace subpackages for ready-to-use implementations.
+type Hooks struct {
+	// OnRequestBegin is called once, before the first attempt at a request
+	// is issued. method and path identify the Stripe endpoint being hit. The
+	// returned context replaces ctx for the remainder of the call (including
+	// any retries and the eventual OnRequestEnd), so a hook can use it to
+	// start a span or attach fields for later hooks to read back out.
+	OnRequestBegin func(ctx context.Context, method, path string) context.Context
+
+	// OnRequestEnd is called once the request, including any retries, has
+	// completed. resp is nil if every attempt failed before a response was
+	// received; err is whatever was ultimately returned to the caller.
+	OnRequestEnd func(ctx context.Context, resp *APIResponse, err error)
+
+	// OnRetry is called before each retry attempt (not the initial attempt),
+	// with the 1-indexed attempt number and the error that triggered it.
+	OnRetry func(ctx context.Context, attempt int, err error)
+}
+
+// beginRequest invokes h.OnRequestBegin, if set, and returns the context it
+// should be replaced with for the rest of the call.
+func (h Hooks) beginRequest(ctx context.Context, method, path string) context.Context {
+	if h.OnRequestBegin == nil {
+		return ctx
+	}
+	return h.OnRequestBegin(ctx, method, path)
+}
+
+// endRequest invokes h.OnRequestEnd, if set.
+func (h Hooks) endRequest(ctx context.Context, resp *APIResponse, err error) {
+	if h.OnRequestEnd != nil {
+		h.OnRequestEnd(ctx, resp, err)
+	}
+}
+
+// retry invokes h.OnRetry, if set.
+func (h Hooks) retry(ctx context.Context, attempt int, err error) {
+	if h.OnRetry != nil {
+		h.OnRetry(ctx, attempt, err)
+	}
+}
@@ -0,0 +1,93 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func withHooks(t *testing.T, hooks Hooks) {
+	t.Helper()
+	prev := currentConfig
+	c := currentConfig
+	c.Hooks = hooks
+	currentConfig = c
+	t.Cleanup(func() { currentConfig = prev })
+}
+
+func TestHooksOnRetryFiresOnEachRetry(t *testing.T) {
+	withFastRetries(t)
+
+	var attempts []int
+	var errs []error
+	withHooks(t, Hooks{
+		OnRetry: func(ctx context.Context, attempt int, err error) {
+			attempts = append(attempts, attempt)
+			errs = append(errs, err)
+		},
+	})
+
+	var served int
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served++
+		if served < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	if err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want := 2; len(attempts) != want {
+		t.Fatalf("expected OnRetry to fire %d times, fired %d times (%v)", want, len(attempts), attempts)
+	}
+	if attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("expected 1-indexed attempts [1 2], got %v", attempts)
+	}
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("attempt %d: expected OnRetry to receive the triggering error", i)
+		}
+	}
+}
+
+func TestHooksOnRequestBeginAndEndFireOnce(t *testing.T) {
+	var begins, ends int
+	withHooks(t, Hooks{
+		OnRequestBegin: func(ctx context.Context, method, path string) context.Context {
+			begins++
+			if method != "GET" || path != "/v1/charges/ch_123" {
+				t.Errorf("unexpected OnRequestBegin args: %s %s", method, path)
+			}
+			return ctx
+		},
+		OnRequestEnd: func(ctx context.Context, resp *APIResponse, err error) {
+			ends++
+			if err != nil {
+				t.Errorf("expected OnRequestEnd to see a nil error, got %s", err.Error())
+			}
+			if resp == nil {
+				t.Error("expected OnRequestEnd to see a non-nil APIResponse")
+			}
+		},
+	})
+
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"ch_123"}`))
+	})
+
+	charge := Charge{}
+	if err := queryCtx(context.Background(), "GET", "/v1/charges/ch_123", nil, &charge); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if begins != 1 {
+		t.Errorf("expected OnRequestBegin to fire once, fired %d times", begins)
+	}
+	if ends != 1 {
+		t.Errorf("expected OnRequestEnd to fire once, fired %d times", ends)
+	}
+}
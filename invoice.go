@@ -1,8 +1,8 @@
 package stripe
 
 import (
+	"context"
 	"net/url"
-	"strconv"
 )
 
 // Invoice represents statements of what a customer owes for a particular
@@ -11,6 +11,7 @@ import (
 //
 // see https://stripe.com/docs/api#invoice_object
 type Invoice struct {
+	APIResource     `json:"-" bson:"-"`
 	Id              string        `json:"id" bson:"id"`
 	AmountDue       int64         `json:"amount_due" bson:"amount_due"`
 	AttemptCount    int           `json:"attempt_count" bson:"attempt_count"`
@@ -22,6 +23,7 @@ type Invoice struct {
 	Subtotal        int64         `json:"subtotal" bson:"subtotal"`
 	Total           int64         `json:"total" bson:"total"`
 	Charge          String        `json:"charge" bson:"charge"`
+	PaymentIntent   String        `json:"payment_intent" bson:"payment_intent"`
 	Customer        string        `json:"closed" bson:"closed"`
 	Date            int64         `json:"date" bson:"date"`
 	Discount        *Discount     `json:"discount" bson:"discount"`
@@ -58,9 +60,17 @@ type InvoiceClient struct{}
 //
 // see https://stripe.com/docs/api#retrieve_invoice
 func (self *InvoiceClient) Retrieve(id string) (*Invoice, error) {
+	return self.RetrieveCtx(context.Background(), id)
+}
+
+// RetrieveCtx retrieves the invoice with the given ID, honoring ctx for
+// cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_invoice
+func (self *InvoiceClient) RetrieveCtx(ctx context.Context, id string) (*Invoice, error) {
 	invoice := Invoice{}
 	path := "/v1/invoices/" + url.QueryEscape(id)
-	err := query("GET", path, nil, &invoice)
+	err := queryCtx(ctx, "GET", path, nil, &invoice)
 	return &invoice, err
 }
 
@@ -68,14 +78,128 @@ func (self *InvoiceClient) Retrieve(id string) (*Invoice, error) {
 //
 // see https://stripe.com/docs/api#retrieve_customer_invoice
 func (self *InvoiceClient) RetrieveCustomer(cid string) (*Invoice, error) {
+	return self.RetrieveCustomerCtx(context.Background(), cid)
+}
+
+// RetrieveCustomerCtx retrieves the upcoming invoice for the given customer
+// ID, honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_customer_invoice
+func (self *InvoiceClient) RetrieveCustomerCtx(ctx context.Context, cid string) (*Invoice, error) {
 	invoice := Invoice{}
 	values := url.Values{"customer": {cid}}
-	err := query("GET", "/v1/invoices/upcoming", values, &invoice)
+	err := queryCtx(ctx, "GET", "/v1/invoices/upcoming", values, &invoice)
 	return &invoice, err
 }
 
+// InvoiceListParams encapsulates options for listing Invoices with
+// cursor-based pagination.
+type InvoiceListParams struct {
+	ListParams
+
+	// (Optional) Only return invoices for this Customer ID.
+	Customer string
+}
+
+// InvoiceIter is a lazily-paginated list of Invoices, as returned by
+// InvoiceClient.Iter. It fetches one page at a time as the caller ranges
+// over it, so it is safe to use over result sets far larger than a single
+// page.
+type InvoiceIter struct {
+	ctx     context.Context
+	params  *InvoiceListParams
+	page    []*Invoice
+	idx     int
+	hasMore bool
+	err     error
+}
+
+// Next advances the iterator to the next Invoice, fetching another page
+// from Stripe if the current one is exhausted. It returns false once there
+// are no more Invoices or an error occurs; check Err to distinguish the
+// two.
+func (it *InvoiceIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	if it.idx < len(it.page) {
+		return true
+	}
+	if it.page != nil && !it.hasMore {
+		return false
+	}
+	if it.page != nil {
+		it.params.StartingAfter = it.page[len(it.page)-1].Id
+	}
+
+	resp, err := invoiceListPage(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Data
+	it.hasMore = resp.HasMore
+	it.idx = 0
+	return len(it.page) > 0
+}
+
+// Invoice returns the Invoice at the iterator's current position. Only
+// valid after a call to Next that returned true.
+func (it *InvoiceIter) Invoice() *Invoice {
+	return it.page[it.idx]
+}
+
+// Err returns the first error encountered while paginating, if any.
+func (it *InvoiceIter) Err() error {
+	return it.err
+}
+
+// Iter returns an InvoiceIter over the Invoices matching the given params,
+// using cursor-based pagination (limit/starting_after/ending_before). Unlike
+// List/ListN, which take count/offset and collect the results into a slice,
+// it fetches each page only as the caller ranges over it.
+//
+// see https://stripe.com/docs/api#list_customer_invoices
+func (self *InvoiceClient) Iter(params *InvoiceListParams) *InvoiceIter {
+	return self.IterCtx(context.Background(), params)
+}
+
+// IterCtx is like Iter, but every page fetched while ranging over the
+// returned InvoiceIter honors ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#list_customer_invoices
+func (self *InvoiceClient) IterCtx(ctx context.Context, params *InvoiceListParams) *InvoiceIter {
+	if params == nil {
+		params = &InvoiceListParams{}
+	}
+	return &InvoiceIter{ctx: ctx, params: params}
+}
+
+type invoiceListResp struct {
+	APIResource `json:"-" bson:"-"`
+	Data        []*Invoice `json:"data"`
+	HasMore     bool       `json:"has_more"`
+}
+
+func invoiceListPage(ctx context.Context, params *InvoiceListParams) (*invoiceListResp, error) {
+	values := url.Values{}
+	params.ListParams.appendTo(&values)
+	if params.Customer != "" {
+		values.Set("customer", params.Customer)
+	}
+
+	resp := invoiceListResp{}
+	err := queryCtx(ctx, "GET", "/v1/invoices", values, &resp)
+	return &resp, err
+}
+
 // Returns a list of Invoices.
 //
+// Deprecated: use Iter, which takes cursor-based ListParams instead of
+// count/offset and fetches pages lazily.
+//
 // see https://stripe.com/docs/api#list_customer_invoices
 func (self *InvoiceClient) List() ([]*Invoice, error) {
 	return self.list("", 10, 0)
@@ -102,26 +226,33 @@ func (self *InvoiceClient) CustomerListN(id string, count int, offset int) ([]*I
 	return self.list(id, count, offset)
 }
 
+// list is the count/offset-compatible implementation behind the deprecated
+// List/ListN methods. It walks the same cursor-based pages Iter uses (count
+// becomes the page Limit, capped at 100) and skips the first offset results
+// on the client side, since Stripe's list endpoints no longer support
+// numeric offsets directly.
 func (self *InvoiceClient) list(id string, count int, offset int) ([]*Invoice, error) {
-	// define a wrapper function for the Invoice List, so that we can
-	// cleanly parse the JSON
-	type listInvoicesResp struct{ Data []*Invoice }
-	resp := listInvoicesResp{}
-
-	// add the count and offset to the list of url values
-	values := url.Values{
-		"count":  {strconv.Itoa(count)},
-		"offset": {strconv.Itoa(offset)},
+	limit := count
+	if limit <= 0 || limit > 100 {
+		limit = 100
 	}
+	params := &InvoiceListParams{ListParams: ListParams{Limit: limit}, Customer: id}
+	iter := self.IterCtx(context.Background(), params)
 
-	// query for customer id, if provided
-	if id != "" {
-		values.Add("customer", id)
+	out := make([]*Invoice, 0, count)
+	skipped := 0
+	for iter.Next() {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		out = append(out, iter.Invoice())
+		if len(out) >= count {
+			break
+		}
 	}
-
-	err := query("GET", "/v1/invoices", values, &resp)
-	if err != nil {
+	if err := iter.Err(); err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	return out, nil
 }
@@ -0,0 +1,72 @@
+package stripe
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ListParams holds the cursor-based pagination parameters shared by every
+// list endpoint. Embed it in a resource-specific params struct (e.g.
+// ChargeListParams) to add filters such as Customer or Created.
+//
+// The legacy List/ListN methods still take count/offset, which they emulate
+// on top of these cursor parameters by skipping offset results on the
+// client side; new code should prefer the Iter method, which exposes the
+// cursors directly and fetches pages lazily.
+type ListParams struct {
+	// Limit is the maximum number of objects to return per page, between 1
+	// and 100. Defaults to 10 if unset.
+	Limit int
+
+	// StartingAfter is a cursor for use in pagination. Pass the Id of the
+	// last object from the previous page to fetch the next page.
+	StartingAfter string
+
+	// EndingBefore is a cursor for use in pagination. Pass the Id of the
+	// first object from the previous page to fetch the previous page.
+	EndingBefore string
+
+	// (Optional) Created restricts results to objects created within the
+	// given range, as a Unix timestamp.
+	Created CreatedRangeParams
+}
+
+// CreatedRangeParams restricts a list endpoint to objects whose Created
+// timestamp falls within the given bounds. Any zero field is omitted from
+// the request, so a partially-filled range (e.g. only Gte) is valid.
+type CreatedRangeParams struct {
+	Gt  int64
+	Gte int64
+	Lt  int64
+	Lte int64
+}
+
+func (p *CreatedRangeParams) appendTo(values *url.Values) {
+	if p.Gt != 0 {
+		values.Set("created[gt]", strconv.FormatInt(p.Gt, 10))
+	}
+	if p.Gte != 0 {
+		values.Set("created[gte]", strconv.FormatInt(p.Gte, 10))
+	}
+	if p.Lt != 0 {
+		values.Set("created[lt]", strconv.FormatInt(p.Lt, 10))
+	}
+	if p.Lte != 0 {
+		values.Set("created[lte]", strconv.FormatInt(p.Lte, 10))
+	}
+}
+
+func (p *ListParams) appendTo(values *url.Values) {
+	limit := p.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	values.Set("limit", strconv.Itoa(limit))
+	if p.StartingAfter != "" {
+		values.Set("starting_after", p.StartingAfter)
+	}
+	if p.EndingBefore != "" {
+		values.Set("ending_before", p.EndingBefore)
+	}
+	p.Created.appendTo(values)
+}
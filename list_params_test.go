@@ -0,0 +1,84 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestListParamsAppendToIncludesCreatedRange(t *testing.T) {
+	p := ListParams{
+		Limit:   5,
+		Created: CreatedRangeParams{Gte: 1000, Lt: 2000},
+	}
+	values := url.Values{}
+	p.appendTo(&values)
+
+	if got := values.Get("created[gte]"); got != "1000" {
+		t.Errorf("expected created[gte]=1000, got %q", got)
+	}
+	if got := values.Get("created[lt]"); got != "2000" {
+		t.Errorf("expected created[lt]=2000, got %q", got)
+	}
+	if values.Has("created[gt]") || values.Has("created[lte]") {
+		t.Error("expected unset range bounds to be omitted")
+	}
+}
+
+func TestPaymentIntentIterPaginatesAcrossPages(t *testing.T) {
+	withFastRetries(t)
+
+	var requests int
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("starting_after") == "" {
+			w.Write([]byte(`{"data":[{"id":"pi_1"},{"id":"pi_2"}],"has_more":true}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"pi_3"}],"has_more":false}`))
+	})
+
+	iter := PaymentIntents.IterCtx(context.Background(), &PaymentIntentListParams{})
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.PaymentIntent().Id)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := []string{"pi_1", "pi_2", "pi_3"}
+	if len(ids) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, ids)
+			break
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestSetupIntentIterStopsWhenNoMorePages(t *testing.T) {
+	withFastRetries(t)
+
+	withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"seti_1"}],"has_more":false}`))
+	})
+
+	iter := SetupIntents.IterCtx(context.Background(), nil)
+	var count int
+	for iter.Next() {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if count != 1 {
+		t.Errorf("expected 1 SetupIntent, got %d", count)
+	}
+}
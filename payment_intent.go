@@ -0,0 +1,339 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// PaymentIntent tracks the lifecycle of a customer checkout flow through
+// Strong Customer Authentication (3DS2), off-session reuse, and any
+// additional actions the customer must take to complete payment.
+//
+// see https://stripe.com/docs/api#payment_intents
+type PaymentIntent struct {
+	APIResource      `json:"-" bson:"-"`
+	Id               string                   `json:"id" bson:"id"`
+	Object           string                   `json:"object" bson:"object"`
+	Amount           int64                    `json:"amount" bson:"amount"`
+	AmountCapturable int64                    `json:"amount_capturable" bson:"amount_capturable"`
+	AmountReceived   int64                    `json:"amount_received" bson:"amount_received"`
+	Currency         string                   `json:"currency" bson:"currency"`
+	Customer         String                   `json:"customer" bson:"customer"`
+	Desc             String                   `json:"description" bson:"description"`
+	Status           string                   `json:"status" bson:"status"` // e.g. requires_payment_method, requires_confirmation, requires_action, processing, requires_capture, canceled, succeeded
+	ClientSecret     string                   `json:"client_secret" bson:"client_secret"`
+	NextAction       *PaymentIntentNextAction `json:"next_action" bson:"next_action"`
+	PaymentMethod    String                   `json:"payment_method" bson:"payment_method"`
+	Charges          *PaymentIntentCharges    `json:"charges" bson:"charges"`
+	Created          int64                    `json:"created" bson:"created"`
+	Livemode         bool                     `json:"livemode" bson:"livemode"`
+}
+
+// PaymentIntentNextAction describes the action the customer must take,
+// outside of the Stripe API, to complete the PaymentIntent (e.g. a 3DS2
+// redirect).
+type PaymentIntentNextAction struct {
+	Type          string                      `json:"type" bson:"type"`
+	RedirectToUrl *PaymentIntentRedirectToUrl `json:"redirect_to_url" bson:"redirect_to_url"`
+}
+
+// PaymentIntentRedirectToUrl holds the URL the customer should be sent to
+// (and the URL Stripe will return them to) in order to authenticate.
+type PaymentIntentRedirectToUrl struct {
+	Url       string `json:"url" bson:"url"`
+	ReturnUrl string `json:"return_url" bson:"return_url"`
+}
+
+// PaymentIntentCharges is the list of Charges created by a PaymentIntent.
+type PaymentIntentCharges struct {
+	Data []*Charge `json:"data" bson:"data"`
+}
+
+// PaymentIntentParams encapsulates options for creating or confirming a
+// PaymentIntent.
+type PaymentIntentParams struct {
+	// A positive integer in cents representing how much to charge the card.
+	Amount int64
+
+	// 3-letter ISO code for currency.
+	Currency string
+
+	// (Optional) The ID of an existing customer this PaymentIntent belongs to.
+	Customer string
+
+	// (Optional) The ID of the PaymentMethod to use when confirming this
+	// PaymentIntent.
+	PaymentMethod string
+
+	// (Optional) Set to true to attempt to confirm this PaymentIntent
+	// immediately upon creation.
+	Confirm bool
+
+	// (Optional) Set to true to indicate that the customer is not present in
+	// the checkout flow (a merchant-initiated off-session charge).
+	OffSession bool
+
+	// An arbitrary string attached to the PaymentIntent, displayed in the web
+	// interface.
+	Desc string
+}
+
+// PaymentIntentClient encapsulates operations for creating, confirming,
+// capturing, canceling and querying PaymentIntents using the Stripe REST
+// API.
+type PaymentIntentClient struct{}
+
+// Creates a new PaymentIntent.
+//
+// see https://stripe.com/docs/api#create_payment_intent
+func (self *PaymentIntentClient) Create(params *PaymentIntentParams) (*PaymentIntent, error) {
+	return self.CreateCtx(context.Background(), params)
+}
+
+// CreateCtx creates a new PaymentIntent, honoring ctx for cancellation,
+// deadlines, and any RequestOptions attached via WithRequestOptions.
+//
+// see https://stripe.com/docs/api#create_payment_intent
+func (self *PaymentIntentClient) CreateCtx(ctx context.Context, params *PaymentIntentParams) (*PaymentIntent, error) {
+	pi := PaymentIntent{}
+	values := paymentIntentParamsToValues(params)
+	err := queryCtx(ctx, "POST", "/v1/payment_intents", values, &pi)
+	return &pi, err
+}
+
+// Retrieves the details of a PaymentIntent with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_payment_intent
+func (self *PaymentIntentClient) Retrieve(id string) (*PaymentIntent, error) {
+	return self.RetrieveCtx(context.Background(), id)
+}
+
+// RetrieveCtx retrieves the details of a PaymentIntent with the given ID,
+// honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_payment_intent
+func (self *PaymentIntentClient) RetrieveCtx(ctx context.Context, id string) (*PaymentIntent, error) {
+	pi := PaymentIntent{}
+	path := "/v1/payment_intents/" + url.QueryEscape(id)
+	err := queryCtx(ctx, "GET", path, nil, &pi)
+	return &pi, err
+}
+
+// Confirms a PaymentIntent, attempting to complete the payment with the
+// given (or previously attached) PaymentMethod.
+//
+// see https://stripe.com/docs/api#confirm_payment_intent
+func (self *PaymentIntentClient) Confirm(id string, paymentMethod string) (*PaymentIntent, error) {
+	return self.ConfirmCtx(context.Background(), id, paymentMethod)
+}
+
+// ConfirmCtx confirms a PaymentIntent, attempting to complete the payment
+// with the given (or previously attached) PaymentMethod, honoring ctx for
+// cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#confirm_payment_intent
+func (self *PaymentIntentClient) ConfirmCtx(ctx context.Context, id string, paymentMethod string) (*PaymentIntent, error) {
+	pi := PaymentIntent{}
+	values := url.Values{}
+	if paymentMethod != "" {
+		values.Add("payment_method", paymentMethod)
+	}
+	path := "/v1/payment_intents/" + url.QueryEscape(id) + "/confirm"
+	err := queryCtx(ctx, "POST", path, values, &pi)
+	return &pi, err
+}
+
+// Captures a PaymentIntent that was created with manual capture and is
+// currently in the requires_capture state.
+//
+// see https://stripe.com/docs/api#capture_payment_intent
+func (self *PaymentIntentClient) Capture(id string) (*PaymentIntent, error) {
+	return self.CaptureCtx(context.Background(), id)
+}
+
+// CaptureCtx captures a PaymentIntent that was created with manual capture
+// and is currently in the requires_capture state, honoring ctx for
+// cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#capture_payment_intent
+func (self *PaymentIntentClient) CaptureCtx(ctx context.Context, id string) (*PaymentIntent, error) {
+	pi := PaymentIntent{}
+	path := "/v1/payment_intents/" + url.QueryEscape(id) + "/capture"
+	err := queryCtx(ctx, "POST", path, url.Values{}, &pi)
+	return &pi, err
+}
+
+// Cancels a PaymentIntent that has not yet succeeded.
+//
+// see https://stripe.com/docs/api#cancel_payment_intent
+func (self *PaymentIntentClient) Cancel(id string) (*PaymentIntent, error) {
+	return self.CancelCtx(context.Background(), id)
+}
+
+// CancelCtx cancels a PaymentIntent that has not yet succeeded, honoring
+// ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#cancel_payment_intent
+func (self *PaymentIntentClient) CancelCtx(ctx context.Context, id string) (*PaymentIntent, error) {
+	pi := PaymentIntent{}
+	path := "/v1/payment_intents/" + url.QueryEscape(id) + "/cancel"
+	err := queryCtx(ctx, "POST", path, url.Values{}, &pi)
+	return &pi, err
+}
+
+// PaymentIntentListParams encapsulates options for listing PaymentIntents
+// with cursor-based pagination.
+type PaymentIntentListParams struct {
+	ListParams
+
+	// (Optional) Only return PaymentIntents for this Customer ID.
+	Customer string
+}
+
+// PaymentIntentIter is a lazily-paginated list of PaymentIntents, as
+// returned by PaymentIntentClient.Iter. It fetches one page at a time as
+// the caller ranges over it, so it is safe to use over result sets far
+// larger than a single page.
+type PaymentIntentIter struct {
+	ctx     context.Context
+	params  *PaymentIntentListParams
+	page    []*PaymentIntent
+	idx     int
+	hasMore bool
+	err     error
+}
+
+// Next advances the iterator to the next PaymentIntent, fetching another
+// page from Stripe if the current one is exhausted. It returns false once
+// there are no more PaymentIntents or an error occurs; check Err to
+// distinguish the two.
+func (it *PaymentIntentIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	if it.idx < len(it.page) {
+		return true
+	}
+	if it.page != nil && !it.hasMore {
+		return false
+	}
+	if it.page != nil {
+		it.params.StartingAfter = it.page[len(it.page)-1].Id
+	}
+
+	resp, err := paymentIntentListPage(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Data
+	it.hasMore = resp.HasMore
+	it.idx = 0
+	return len(it.page) > 0
+}
+
+// PaymentIntent returns the PaymentIntent at the iterator's current
+// position. Only valid after a call to Next that returned true.
+func (it *PaymentIntentIter) PaymentIntent() *PaymentIntent {
+	return it.page[it.idx]
+}
+
+// Err returns the first error encountered while paginating, if any.
+func (it *PaymentIntentIter) Err() error {
+	return it.err
+}
+
+// Iter returns a PaymentIntentIter over the PaymentIntents matching the
+// given params, using cursor-based pagination
+// (limit/starting_after/ending_before) to walk every page, unlike List and
+// CustomerList which only return the first one.
+//
+// see https://stripe.com/docs/api#list_payment_intents
+func (self *PaymentIntentClient) Iter(params *PaymentIntentListParams) *PaymentIntentIter {
+	return self.IterCtx(context.Background(), params)
+}
+
+// IterCtx is like Iter, but every page fetched while ranging over the
+// returned PaymentIntentIter honors ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#list_payment_intents
+func (self *PaymentIntentClient) IterCtx(ctx context.Context, params *PaymentIntentListParams) *PaymentIntentIter {
+	if params == nil {
+		params = &PaymentIntentListParams{}
+	}
+	return &PaymentIntentIter{ctx: ctx, params: params}
+}
+
+type paymentIntentListResp struct {
+	APIResource `json:"-" bson:"-"`
+	Data        []*PaymentIntent `json:"data"`
+	HasMore     bool             `json:"has_more"`
+}
+
+func paymentIntentListPage(ctx context.Context, params *PaymentIntentListParams) (*paymentIntentListResp, error) {
+	values := url.Values{}
+	params.ListParams.appendTo(&values)
+	if params.Customer != "" {
+		values.Set("customer", params.Customer)
+	}
+
+	resp := paymentIntentListResp{}
+	err := queryCtx(ctx, "GET", "/v1/payment_intents", values, &resp)
+	return &resp, err
+}
+
+// Returns the first page of your PaymentIntents.
+//
+// Deprecated: use Iter, which walks every page via cursor-based pagination
+// instead of returning just the first one.
+//
+// see https://stripe.com/docs/api#list_payment_intents
+func (self *PaymentIntentClient) List() ([]*PaymentIntent, error) {
+	resp, err := paymentIntentListPage(context.Background(), &PaymentIntentListParams{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Returns the first page of your PaymentIntents for the given Customer ID.
+//
+// Deprecated: use Iter with PaymentIntentListParams.Customer set, which
+// walks every page via cursor-based pagination instead of returning just
+// the first one.
+//
+// see https://stripe.com/docs/api#list_payment_intents
+func (self *PaymentIntentClient) CustomerList(id string) ([]*PaymentIntent, error) {
+	resp, err := paymentIntentListPage(context.Background(), &PaymentIntentListParams{Customer: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func paymentIntentParamsToValues(params *PaymentIntentParams) url.Values {
+	values := url.Values{
+		"amount":      {strconv.FormatInt(params.Amount, 10)},
+		"currency":    {params.Currency},
+		"description": {params.Desc},
+	}
+	if params.Customer != "" {
+		values.Add("customer", params.Customer)
+	}
+	if params.PaymentMethod != "" {
+		values.Add("payment_method", params.PaymentMethod)
+	}
+	if params.Confirm {
+		values.Add("confirm", "true")
+	}
+	if params.OffSession {
+		values.Add("off_session", "true")
+	}
+	return values
+}
+
+// PaymentIntents is the client used to invoke PaymentIntent related APIs.
+var PaymentIntents = &PaymentIntentClient{}
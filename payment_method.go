@@ -0,0 +1,123 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+)
+
+// PaymentMethod represents a customer's payment instrument that has been
+// saved for later (re)use, such as a card attached via a PaymentIntent or
+// SetupIntent confirmation.
+//
+// see https://stripe.com/docs/api#payment_methods
+type PaymentMethod struct {
+	APIResource `json:"-" bson:"-"`
+	Id          string `json:"id" bson:"id"`
+	Object      string `json:"object" bson:"object"`
+	Type        string `json:"type" bson:"type"` // currently only "card" is supported
+	Card        *Card  `json:"card" bson:"card"`
+	Customer    String `json:"customer" bson:"customer"`
+	Created     int64  `json:"created" bson:"created"`
+	Livemode    bool   `json:"livemode" bson:"livemode"`
+}
+
+// PaymentMethodParams encapsulates options for creating a new PaymentMethod.
+type PaymentMethodParams struct {
+	// The type of PaymentMethod. Only "card" is currently supported.
+	Type string
+
+	// (Optional) Credit Card details for a "card" type PaymentMethod.
+	Card *CardParams
+
+	// (Optional) The ID of a customer. Create does not send this field; use
+	// Attach to attach the created PaymentMethod to a customer.
+	Customer string
+}
+
+// PaymentMethodClient encapsulates operations for creating, retrieving and
+// attaching PaymentMethods using the Stripe REST API.
+type PaymentMethodClient struct{}
+
+// Creates a new PaymentMethod.
+//
+// see https://stripe.com/docs/api#create_payment_method
+func (self *PaymentMethodClient) Create(params *PaymentMethodParams) (*PaymentMethod, error) {
+	return self.CreateCtx(context.Background(), params)
+}
+
+// CreateCtx creates a new PaymentMethod, honoring ctx for cancellation,
+// deadlines, and any RequestOptions attached via WithRequestOptions.
+//
+// see https://stripe.com/docs/api#create_payment_method
+func (self *PaymentMethodClient) CreateCtx(ctx context.Context, params *PaymentMethodParams) (*PaymentMethod, error) {
+	pm := PaymentMethod{}
+	values := url.Values{
+		"type": {params.Type},
+	}
+	if params.Card != nil {
+		appendCardParamsToValues(params.Card, &values)
+	}
+
+	err := queryCtx(ctx, "POST", "/v1/payment_methods", values, &pm)
+	return &pm, err
+}
+
+// Retrieves the details of a PaymentMethod with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_payment_method
+func (self *PaymentMethodClient) Retrieve(id string) (*PaymentMethod, error) {
+	return self.RetrieveCtx(context.Background(), id)
+}
+
+// RetrieveCtx retrieves the details of a PaymentMethod with the given ID,
+// honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_payment_method
+func (self *PaymentMethodClient) RetrieveCtx(ctx context.Context, id string) (*PaymentMethod, error) {
+	pm := PaymentMethod{}
+	path := "/v1/payment_methods/" + url.QueryEscape(id)
+	err := queryCtx(ctx, "GET", path, nil, &pm)
+	return &pm, err
+}
+
+// Attaches a PaymentMethod to a Customer, so it can be reused for
+// off-session charges.
+//
+// see https://stripe.com/docs/api#attach_payment_method
+func (self *PaymentMethodClient) Attach(id string, customer string) (*PaymentMethod, error) {
+	return self.AttachCtx(context.Background(), id, customer)
+}
+
+// AttachCtx attaches a PaymentMethod to a Customer, so it can be reused for
+// off-session charges, honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#attach_payment_method
+func (self *PaymentMethodClient) AttachCtx(ctx context.Context, id string, customer string) (*PaymentMethod, error) {
+	pm := PaymentMethod{}
+	values := url.Values{"customer": {customer}}
+	path := "/v1/payment_methods/" + url.QueryEscape(id) + "/attach"
+	err := queryCtx(ctx, "POST", path, values, &pm)
+	return &pm, err
+}
+
+// Detaches a PaymentMethod from whichever Customer it is currently attached
+// to.
+//
+// see https://stripe.com/docs/api#detach_payment_method
+func (self *PaymentMethodClient) Detach(id string) (*PaymentMethod, error) {
+	return self.DetachCtx(context.Background(), id)
+}
+
+// DetachCtx detaches a PaymentMethod from whichever Customer it is currently
+// attached to, honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#detach_payment_method
+func (self *PaymentMethodClient) DetachCtx(ctx context.Context, id string) (*PaymentMethod, error) {
+	pm := PaymentMethod{}
+	path := "/v1/payment_methods/" + url.QueryEscape(id) + "/detach"
+	err := queryCtx(ctx, "POST", path, url.Values{}, &pm)
+	return &pm, err
+}
+
+// PaymentMethods is the client used to invoke PaymentMethod related APIs.
+var PaymentMethods = &PaymentMethodClient{}
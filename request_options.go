@@ -0,0 +1,32 @@
+package stripe
+
+// RequestOption mutates the options for a single call to query(). Currently
+// the only option is WithIdempotencyKey.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds per-call overrides applied on top of Config.
+type requestOptions struct {
+	idempotencyKey string
+}
+
+// WithIdempotencyKey attaches the given key as the Idempotency-Key header on
+// the request, so that Stripe recognizes retried or resent POST/DELETE
+// requests as the same logical operation rather than applying them twice. If
+// no key is supplied, query() generates one per logical call so that its own
+// internal retries are always safe.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+func buildRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.idempotencyKey == "" {
+		o.idempotencyKey = newIdempotencyKey()
+	}
+	return o
+}
@@ -0,0 +1,43 @@
+package stripe
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// newIdempotencyKey generates a random v4 UUID to use as an Idempotency-Key
+// header when the caller hasn't supplied one of their own via
+// WithIdempotencyKey.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand failing is effectively unrecoverable on any supported
+		// platform; fall back to a timestamp-based key rather than sending
+		// no Idempotency-Key at all.
+		return fmt.Sprintf("fallback-%d", time.Now().UnixNano())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// backoffDuration returns the exponential backoff delay to wait before retry
+// attempt (0-indexed). The exponential delay is capped at max, then up to 25%
+// jitter is added to avoid thundering-herd retries, so the result can reach
+// max plus a quarter.
+func backoffDuration(attempt int, max time.Duration) time.Duration {
+	base := time.Duration(math.Pow(2, float64(attempt))) * 100 * time.Millisecond
+	if base > max {
+		base = max
+	}
+
+	jitter, err := rand.Int(rand.Reader, big.NewInt(int64(base)/4+1))
+	if err != nil {
+		return base
+	}
+	return base + time.Duration(jitter.Int64())
+}
@@ -0,0 +1,46 @@
+package stripe
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffDurationCapsAtMax(t *testing.T) {
+	max := 500 * time.Millisecond
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffDuration(attempt, max)
+		if d > max+max/4 {
+			t.Fatalf("attempt %d: backoffDuration %s exceeds max %s plus jitter", attempt, d, max)
+		}
+	}
+}
+
+func TestBackoffDurationIncreasesWithAttempt(t *testing.T) {
+	max := time.Minute
+	first := backoffDuration(0, max)
+	later := backoffDuration(4, max)
+	if later <= first {
+		t.Errorf("expected backoffDuration to grow with attempt, got attempt 0 = %s, attempt 4 = %s", first, later)
+	}
+}
+
+func TestNewIdempotencyKeyFormat(t *testing.T) {
+	key := newIdempotencyKey()
+	parts := strings.Split(key, "-")
+	if len(parts) != 5 {
+		t.Fatalf("expected a UUID-shaped key with 5 dash-separated groups, got %q", key)
+	}
+	lens := []int{8, 4, 4, 4, 12}
+	for i, want := range lens {
+		if len(parts[i]) != want {
+			t.Errorf("group %d: expected length %d, got %q", i, want, parts[i])
+		}
+	}
+}
+
+func TestNewIdempotencyKeyUnique(t *testing.T) {
+	if newIdempotencyKey() == newIdempotencyKey() {
+		t.Error("expected two calls to newIdempotencyKey to return different keys")
+	}
+}
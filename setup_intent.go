@@ -0,0 +1,258 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+)
+
+// SetupIntent tracks the process of collecting and confirming a customer's
+// payment method for future (off-session) use, without charging them
+// immediately.
+//
+// see https://stripe.com/docs/api#setup_intents
+type SetupIntent struct {
+	APIResource   `json:"-" bson:"-"`
+	Id            string                   `json:"id" bson:"id"`
+	Object        string                   `json:"object" bson:"object"`
+	Customer      String                   `json:"customer" bson:"customer"`
+	Status        string                   `json:"status" bson:"status"` // e.g. requires_payment_method, requires_confirmation, requires_action, processing, canceled, succeeded
+	Usage         string                   `json:"usage" bson:"usage"`   // off_session or on_session
+	ClientSecret  string                   `json:"client_secret" bson:"client_secret"`
+	NextAction    *PaymentIntentNextAction `json:"next_action" bson:"next_action"`
+	PaymentMethod String                   `json:"payment_method" bson:"payment_method"`
+	Created       int64                    `json:"created" bson:"created"`
+	Livemode      bool                     `json:"livemode" bson:"livemode"`
+}
+
+// SetupIntentParams encapsulates options for creating or confirming a
+// SetupIntent.
+type SetupIntentParams struct {
+	// (Optional) The ID of an existing customer this SetupIntent belongs to.
+	Customer string
+
+	// (Optional) The ID of the PaymentMethod to attach to this SetupIntent.
+	PaymentMethod string
+
+	// (Optional) Set to true to attempt to confirm this SetupIntent
+	// immediately upon creation.
+	Confirm bool
+
+	// (Optional) Indicates how the payment method will be used outside of
+	// the checkout session. Defaults to "off_session".
+	Usage string
+}
+
+// SetupIntentClient encapsulates operations for creating, confirming,
+// canceling and querying SetupIntents using the Stripe REST API.
+type SetupIntentClient struct{}
+
+// Creates a new SetupIntent.
+//
+// see https://stripe.com/docs/api#create_setup_intent
+func (self *SetupIntentClient) Create(params *SetupIntentParams) (*SetupIntent, error) {
+	return self.CreateCtx(context.Background(), params)
+}
+
+// CreateCtx creates a new SetupIntent, honoring ctx for cancellation,
+// deadlines, and any RequestOptions attached via WithRequestOptions.
+//
+// see https://stripe.com/docs/api#create_setup_intent
+func (self *SetupIntentClient) CreateCtx(ctx context.Context, params *SetupIntentParams) (*SetupIntent, error) {
+	si := SetupIntent{}
+	values := url.Values{}
+	if params.Customer != "" {
+		values.Add("customer", params.Customer)
+	}
+	if params.PaymentMethod != "" {
+		values.Add("payment_method", params.PaymentMethod)
+	}
+	if params.Confirm {
+		values.Add("confirm", "true")
+	}
+	if params.Usage != "" {
+		values.Add("usage", params.Usage)
+	}
+
+	err := queryCtx(ctx, "POST", "/v1/setup_intents", values, &si)
+	return &si, err
+}
+
+// Retrieves the details of a SetupIntent with the given ID.
+//
+// see https://stripe.com/docs/api#retrieve_setup_intent
+func (self *SetupIntentClient) Retrieve(id string) (*SetupIntent, error) {
+	return self.RetrieveCtx(context.Background(), id)
+}
+
+// RetrieveCtx retrieves the details of a SetupIntent with the given ID,
+// honoring ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#retrieve_setup_intent
+func (self *SetupIntentClient) RetrieveCtx(ctx context.Context, id string) (*SetupIntent, error) {
+	si := SetupIntent{}
+	path := "/v1/setup_intents/" + url.QueryEscape(id)
+	err := queryCtx(ctx, "GET", path, nil, &si)
+	return &si, err
+}
+
+// Confirms a SetupIntent, attempting to attach the given (or previously
+// attached) PaymentMethod for future use.
+//
+// see https://stripe.com/docs/api#confirm_setup_intent
+func (self *SetupIntentClient) Confirm(id string, paymentMethod string) (*SetupIntent, error) {
+	return self.ConfirmCtx(context.Background(), id, paymentMethod)
+}
+
+// ConfirmCtx confirms a SetupIntent, attempting to attach the given (or
+// previously attached) PaymentMethod for future use, honoring ctx for
+// cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#confirm_setup_intent
+func (self *SetupIntentClient) ConfirmCtx(ctx context.Context, id string, paymentMethod string) (*SetupIntent, error) {
+	si := SetupIntent{}
+	values := url.Values{}
+	if paymentMethod != "" {
+		values.Add("payment_method", paymentMethod)
+	}
+	path := "/v1/setup_intents/" + url.QueryEscape(id) + "/confirm"
+	err := queryCtx(ctx, "POST", path, values, &si)
+	return &si, err
+}
+
+// Cancels a SetupIntent that has not yet succeeded.
+//
+// see https://stripe.com/docs/api#cancel_setup_intent
+func (self *SetupIntentClient) Cancel(id string) (*SetupIntent, error) {
+	return self.CancelCtx(context.Background(), id)
+}
+
+// CancelCtx cancels a SetupIntent that has not yet succeeded, honoring ctx
+// for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#cancel_setup_intent
+func (self *SetupIntentClient) CancelCtx(ctx context.Context, id string) (*SetupIntent, error) {
+	si := SetupIntent{}
+	path := "/v1/setup_intents/" + url.QueryEscape(id) + "/cancel"
+	err := queryCtx(ctx, "POST", path, url.Values{}, &si)
+	return &si, err
+}
+
+// SetupIntentListParams encapsulates options for listing SetupIntents with
+// cursor-based pagination.
+type SetupIntentListParams struct {
+	ListParams
+
+	// (Optional) Only return SetupIntents for this Customer ID.
+	Customer string
+}
+
+// SetupIntentIter is a lazily-paginated list of SetupIntents, as returned
+// by SetupIntentClient.Iter. It fetches one page at a time as the caller
+// ranges over it, so it is safe to use over result sets far larger than a
+// single page.
+type SetupIntentIter struct {
+	ctx     context.Context
+	params  *SetupIntentListParams
+	page    []*SetupIntent
+	idx     int
+	hasMore bool
+	err     error
+}
+
+// Next advances the iterator to the next SetupIntent, fetching another
+// page from Stripe if the current one is exhausted. It returns false once
+// there are no more SetupIntents or an error occurs; check Err to
+// distinguish the two.
+func (it *SetupIntentIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	if it.idx < len(it.page) {
+		return true
+	}
+	if it.page != nil && !it.hasMore {
+		return false
+	}
+	if it.page != nil {
+		it.params.StartingAfter = it.page[len(it.page)-1].Id
+	}
+
+	resp, err := setupIntentListPage(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Data
+	it.hasMore = resp.HasMore
+	it.idx = 0
+	return len(it.page) > 0
+}
+
+// SetupIntent returns the SetupIntent at the iterator's current position.
+// Only valid after a call to Next that returned true.
+func (it *SetupIntentIter) SetupIntent() *SetupIntent {
+	return it.page[it.idx]
+}
+
+// Err returns the first error encountered while paginating, if any.
+func (it *SetupIntentIter) Err() error {
+	return it.err
+}
+
+// Iter returns a SetupIntentIter over the SetupIntents matching the given
+// params, using cursor-based pagination
+// (limit/starting_after/ending_before) to walk every page, unlike List
+// which only returns the first one.
+//
+// see https://stripe.com/docs/api#list_setup_intents
+func (self *SetupIntentClient) Iter(params *SetupIntentListParams) *SetupIntentIter {
+	return self.IterCtx(context.Background(), params)
+}
+
+// IterCtx is like Iter, but every page fetched while ranging over the
+// returned SetupIntentIter honors ctx for cancellation and deadlines.
+//
+// see https://stripe.com/docs/api#list_setup_intents
+func (self *SetupIntentClient) IterCtx(ctx context.Context, params *SetupIntentListParams) *SetupIntentIter {
+	if params == nil {
+		params = &SetupIntentListParams{}
+	}
+	return &SetupIntentIter{ctx: ctx, params: params}
+}
+
+type setupIntentListResp struct {
+	APIResource `json:"-" bson:"-"`
+	Data        []*SetupIntent `json:"data"`
+	HasMore     bool           `json:"has_more"`
+}
+
+func setupIntentListPage(ctx context.Context, params *SetupIntentListParams) (*setupIntentListResp, error) {
+	values := url.Values{}
+	params.ListParams.appendTo(&values)
+	if params.Customer != "" {
+		values.Set("customer", params.Customer)
+	}
+
+	resp := setupIntentListResp{}
+	err := queryCtx(ctx, "GET", "/v1/setup_intents", values, &resp)
+	return &resp, err
+}
+
+// Returns the first page of your SetupIntents.
+//
+// Deprecated: use Iter, which walks every page via cursor-based pagination
+// instead of returning just the first one.
+//
+// see https://stripe.com/docs/api#list_setup_intents
+func (self *SetupIntentClient) List() ([]*SetupIntent, error) {
+	resp, err := setupIntentListPage(context.Background(), &SetupIntentListParams{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// SetupIntents is the client used to invoke SetupIntent related APIs.
+var SetupIntents = &SetupIntentClient{}
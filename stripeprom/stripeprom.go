@@ -0,0 +1,102 @@
+// Package stripeprom is a ready-to-use example of wiring stripe.Hooks up to
+// Prometheus, so that production users can measure Stripe request latency
+// and failure rates without the core stripe package taking a hard
+// dependency on the Prometheus client library.
+package stripeprom
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ChannelMeter/go.stripe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector holds the Prometheus metrics populated by Hooks. NewCollector
+// registers them with the given registry.
+type Collector struct {
+	// Requests counts completed requests, labeled by endpoint and the
+	// outcome's HTTP status (or "error" when no response was received).
+	Requests *prometheus.CounterVec
+
+	// Retries counts retry attempts, labeled by endpoint.
+	Retries *prometheus.CounterVec
+
+	// Latency observes request duration in seconds, labeled by endpoint.
+	Latency *prometheus.HistogramVec
+}
+
+// NewCollector builds a Collector with metrics named in the "stripe"
+// namespace and registers them with reg.
+func NewCollector(reg prometheus.Registerer) *Collector {
+	c := &Collector{
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "stripe",
+			Name:      "requests_total",
+			Help:      "Total Stripe API requests, by endpoint and status.",
+		}, []string{"endpoint", "status"}),
+		Retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "stripe",
+			Name:      "request_retries_total",
+			Help:      "Total Stripe API request retries, by endpoint.",
+		}, []string{"endpoint"}),
+		Latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "stripe",
+			Name:      "request_duration_seconds",
+			Help:      "Stripe API request latency in seconds, by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+	}
+	reg.MustRegister(c.Requests, c.Retries, c.Latency)
+	return c
+}
+
+// callState carries the bookkeeping OnRequestBegin stashes in the context
+// for OnRequestEnd and OnRetry to read back out.
+type callState struct {
+	endpoint string
+	start    time.Time
+}
+
+type callStateKey struct{}
+
+// Hooks returns a stripe.Hooks that records every request against c. Install
+// it with stripe.SetConfig once at startup.
+func (c *Collector) Hooks() stripe.Hooks {
+	return stripe.Hooks{
+		OnRequestBegin: func(ctx context.Context, method, path string) context.Context {
+			return context.WithValue(ctx, callStateKey{}, &callState{
+				endpoint: method + " " + path,
+				start:    time.Now(),
+			})
+		},
+		OnRequestEnd: func(ctx context.Context, resp *stripe.APIResponse, err error) {
+			state, _ := ctx.Value(callStateKey{}).(*callState)
+			endpoint := endpointOf(state)
+
+			status := "error"
+			if resp != nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+			c.Requests.WithLabelValues(endpoint, status).Inc()
+
+			if state != nil {
+				c.Latency.WithLabelValues(endpoint).Observe(time.Since(state.start).Seconds())
+			}
+		},
+		OnRetry: func(ctx context.Context, attempt int, err error) {
+			state, _ := ctx.Value(callStateKey{}).(*callState)
+			c.Retries.WithLabelValues(endpointOf(state)).Inc()
+		},
+	}
+}
+
+// endpointOf returns state.endpoint, or "unknown" if the context carries no
+// callState from OnRequestBegin.
+func endpointOf(state *callState) string {
+	if state == nil {
+		return "unknown"
+	}
+	return state.endpoint
+}
@@ -0,0 +1,77 @@
+// Package stripetrace is a ready-to-use example of wiring stripe.Hooks up
+// to net/http/httptrace, so that production users can log or export
+// per-connection timing (DNS, TLS, first byte, ...) for Stripe requests
+// without the core stripe package depending on any particular tracing
+// backend.
+package stripetrace
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/ChannelMeter/go.stripe"
+)
+
+// Timing records the wall-clock time at which each phase of a single Stripe
+// request's underlying HTTP connection occurred; subtract Start to get the
+// elapsed time of a phase.
+type Timing struct {
+	Method, Path string
+
+	Start        time.Time
+	GotConn      time.Time
+	DNSDone      time.Time
+	ConnectDone  time.Time
+	TLSDone      time.Time
+	WroteRequest time.Time
+	FirstByte    time.Time
+	Done         time.Time
+
+	Err error
+}
+
+// Reporter is called once a Timing is complete, with the final request
+// error (if any) already attached.
+type Reporter func(Timing)
+
+// Hooks returns a stripe.Hooks that attaches an httptrace.ClientTrace to
+// each outgoing request's context and reports the resulting Timing to
+// report once the request finishes.
+//
+// Install it with stripe.SetConfig once at startup:
+//
+//	stripe.SetConfig(stripe.Config{Hooks: stripetrace.Hooks(func(t stripetrace.Timing) {
+//		log.Printf("%s %s took %s", t.Method, t.Path, t.Done.Sub(t.Start))
+//	})})
+func Hooks(report Reporter) stripe.Hooks {
+	return stripe.Hooks{
+		OnRequestBegin: func(ctx context.Context, method, path string) context.Context {
+			timing := &Timing{Method: method, Path: path, Start: time.Now()}
+
+			trace := &httptrace.ClientTrace{
+				GotConn:              func(httptrace.GotConnInfo) { timing.GotConn = time.Now() },
+				DNSDone:              func(httptrace.DNSDoneInfo) { timing.DNSDone = time.Now() },
+				ConnectDone:          func(string, string, error) { timing.ConnectDone = time.Now() },
+				TLSHandshakeDone:     func(tls.ConnectionState, error) { timing.TLSDone = time.Now() },
+				WroteRequest:         func(httptrace.WroteRequestInfo) { timing.WroteRequest = time.Now() },
+				GotFirstResponseByte: func() { timing.FirstByte = time.Now() },
+			}
+
+			ctx = context.WithValue(ctx, timingKey{}, timing)
+			return httptrace.WithClientTrace(ctx, trace)
+		},
+		OnRequestEnd: func(ctx context.Context, resp *stripe.APIResponse, err error) {
+			timing, ok := ctx.Value(timingKey{}).(*Timing)
+			if !ok {
+				return
+			}
+			timing.Done = time.Now()
+			timing.Err = err
+			report(*timing)
+		},
+	}
+}
+
+type timingKey struct{}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Handler adapts a Router to the standard library http.Handler interface:
+// it reads the request body, verifies the Stripe-Signature header against
+// Secret, and dispatches the resulting Event to Router before responding
+// 200 OK. Any other response tells Stripe to retry delivery.
+type Handler struct {
+	// Secret is the webhook endpoint's signing secret, from the Stripe
+	// dashboard.
+	Secret string
+
+	// Router dispatches verified events to the handlers registered with it.
+	Router *Router
+
+	// Tolerance overrides DefaultTolerance for the allowed age of the
+	// signed timestamp. Zero means DefaultTolerance.
+	Tolerance time.Duration
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusServiceUnavailable)
+		return
+	}
+
+	tolerance := h.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	event, err := ConstructEventWithTolerance(payload, r.Header.Get("Stripe-Signature"), h.Secret, tolerance)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.Router != nil {
+		if err := h.Router.Dispatch(r.Context(), event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import "context"
+
+// HandlerFunc is called with a verified Event of the type it was registered
+// for.
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// Router dispatches verified webhook Events to per-type handlers.
+type Router struct {
+	handlers map[string][]HandlerFunc
+	fallback []HandlerFunc
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{handlers: make(map[string][]HandlerFunc)}
+}
+
+// On registers fn to run whenever an Event of the given type (e.g.
+// "charge.succeeded") is dispatched.
+func (r *Router) On(eventType string, fn HandlerFunc) {
+	r.handlers[eventType] = append(r.handlers[eventType], fn)
+}
+
+// OnAny registers fn to run for every Event, regardless of type. Fallback
+// handlers run after any type-specific handlers.
+func (r *Router) OnAny(fn HandlerFunc) {
+	r.fallback = append(r.fallback, fn)
+}
+
+// Dispatch invokes every handler registered for event.Type, followed by any
+// registered with OnAny. The first error returned by a handler stops
+// dispatch and is returned to the caller.
+func (r *Router) Dispatch(ctx context.Context, event Event) error {
+	for _, fn := range r.handlers[event.Type] {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	for _, fn := range r.fallback {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package webhook verifies and parses Stripe webhook events, so that
+// services can react to asynchronous events like charge.succeeded or
+// invoice.payment_failed without polling the API.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTolerance is the maximum age a webhook timestamp may have before
+// ConstructEvent rejects it as a possible replay.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrNoValidSignature is returned when none of the v1 signatures in the
+	// Stripe-Signature header match the payload and secret.
+	ErrNoValidSignature = errors.New("webhook: no valid signature found for the given payload and secret")
+
+	// ErrTimestampTooOld is returned when the signed timestamp falls outside
+	// the configured tolerance.
+	ErrTimestampTooOld = errors.New("webhook: timestamp outside the allowed tolerance")
+)
+
+// Event represents a Stripe webhook event, as delivered in the body of a
+// request to an endpoint and signed via the Stripe-Signature header.
+//
+// see https://stripe.com/docs/api#event_object
+type Event struct {
+	Id         string    `json:"id"`
+	Type       string    `json:"type"`
+	Created    int64     `json:"created"`
+	Livemode   bool      `json:"livemode"`
+	APIVersion string    `json:"api_version"`
+	Data       EventData `json:"data"`
+}
+
+// EventData holds the object the event is about, still encoded as raw JSON
+// so that callers can unmarshal it into the concrete resource type (Charge,
+// Invoice, Customer, Subscription, ...) they expect for event.Type.
+type EventData struct {
+	Raw json.RawMessage `json:"object"`
+}
+
+// DataAs unmarshals the event's data.object into dst, which should be a
+// pointer to the concrete Stripe resource type expected for event.Type
+// (e.g. &stripe.Charge{} for a "charge.succeeded" event).
+func (e *Event) DataAs(dst interface{}) error {
+	return json.Unmarshal(e.Data.Raw, dst)
+}
+
+// ConstructEvent verifies the signature on sigHeader (the value of the
+// Stripe-Signature request header) against payload using secret, the
+// endpoint's signing secret, and returns the parsed Event. Timestamps older
+// than DefaultTolerance are rejected.
+//
+// see https://stripe.com/docs/webhooks/signatures
+func ConstructEvent(payload []byte, sigHeader string, secret string) (Event, error) {
+	return ConstructEventWithTolerance(payload, sigHeader, secret, DefaultTolerance)
+}
+
+// ConstructEventWithTolerance is like ConstructEvent, but allows the caller
+// to override the default replay tolerance. A tolerance of 0 disables the
+// timestamp check entirely.
+func ConstructEventWithTolerance(payload []byte, sigHeader string, secret string, tolerance time.Duration) (Event, error) {
+	var event Event
+
+	ts, sigs, err := parseSignatureHeader(sigHeader)
+	if err != nil {
+		return event, err
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return event, ErrTimestampTooOld
+		}
+	}
+
+	signedPayload := strconv.FormatInt(ts, 10) + "." + string(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signedPayload))
+	expected := mac.Sum(nil)
+
+	valid := false
+	for _, sig := range sigs {
+		given, err := hex.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		// constant-time comparison to avoid leaking the expected signature
+		// through response-time side channels
+		if hmac.Equal(expected, given) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return event, ErrNoValidSignature
+	}
+
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, fmt.Errorf("webhook: failed to parse payload: %w", err)
+	}
+	return event, nil
+}
+
+// parseSignatureHeader parses a Stripe-Signature header of the form
+// "t=<timestamp>,v1=<signature>[,v1=<signature>...]", tolerating multiple
+// v1 signatures so that secrets can be rotated without dropping events
+// signed with the outgoing secret.
+func parseSignatureHeader(header string) (int64, []string, error) {
+	var (
+		ts    int64
+		tsSet bool
+		sigs  []string
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		pieces := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pieces) != 2 {
+			continue
+		}
+		switch pieces[0] {
+		case "t":
+			v, err := strconv.ParseInt(pieces[1], 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("webhook: invalid timestamp %q: %w", pieces[1], err)
+			}
+			ts = v
+			tsSet = true
+		case "v1":
+			sigs = append(sigs, pieces[1])
+		}
+	}
+
+	if !tsSet || len(sigs) == 0 {
+		return 0, nil, ErrNoValidSignature
+	}
+	return ts, sigs, nil
+}
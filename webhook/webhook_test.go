@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const testSecret = "whsec_test"
+
+func sign(payload []byte, secret string, ts int64) string {
+	signedPayload := strconv.FormatInt(ts, 10) + "." + string(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signedPayload))
+	return "t=" + strconv.FormatInt(ts, 10) + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestConstructEventValidSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_123","type":"charge.succeeded","data":{"object":{}}}`)
+	header := sign(payload, testSecret, time.Now().Unix())
+
+	event, err := ConstructEvent(payload, header, testSecret)
+	if err != nil {
+		t.Fatalf("expected valid signature, got error %s", err.Error())
+	}
+	if event.Type != "charge.succeeded" {
+		t.Errorf("expected Type %q, got %q", "charge.succeeded", event.Type)
+	}
+}
+
+func TestConstructEventInvalidSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_123","type":"charge.succeeded","data":{"object":{}}}`)
+	header := sign(payload, "wrong-secret", time.Now().Unix())
+
+	if _, err := ConstructEvent(payload, header, testSecret); err != ErrNoValidSignature {
+		t.Errorf("expected ErrNoValidSignature, got %v", err)
+	}
+}
+
+func TestConstructEventStaleTimestamp(t *testing.T) {
+	payload := []byte(`{"id":"evt_123","type":"charge.succeeded","data":{"object":{}}}`)
+	header := sign(payload, testSecret, time.Now().Add(-10*time.Minute).Unix())
+
+	if _, err := ConstructEvent(payload, header, testSecret); err != ErrTimestampTooOld {
+		t.Errorf("expected ErrTimestampTooOld, got %v", err)
+	}
+}
+
+func TestConstructEventToleratesRotatedSecret(t *testing.T) {
+	payload := []byte(`{"id":"evt_123","type":"charge.succeeded","data":{"object":{}}}`)
+	ts := time.Now().Unix()
+
+	mac := func(secret string) string {
+		signedPayload := strconv.FormatInt(ts, 10) + "." + string(payload)
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write([]byte(signedPayload))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	header := "t=" + strconv.FormatInt(ts, 10) + ",v1=" + mac("old-secret") + ",v1=" + mac(testSecret)
+
+	event, err := ConstructEvent(payload, header, testSecret)
+	if err != nil {
+		t.Fatalf("expected new secret's signature to validate, got error %s", err.Error())
+	}
+	if event.Id != "evt_123" {
+		t.Errorf("expected Id %q, got %q", "evt_123", event.Id)
+	}
+}